@@ -0,0 +1,48 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+// Config is a top level Vendir configuration document read
+// from the vendir.yml file (or piped in via stdin).
+type Config struct {
+	APIVersion  string `json:"apiVersion"`
+	Kind        string `json:"kind"`
+	Directories []Directory
+}
+
+// Directory describes a single destination directory and the
+// contents that should be synced into it.
+type Directory struct {
+	Path     string
+	Contents []DirectoryContents
+}
+
+// DirectoryContents describes a single source of content to be
+// placed at Path within the owning Directory. Exactly one of the
+// content type fields below is expected to be set.
+type DirectoryContents struct {
+	Path string
+
+	Git          *DirectoryContentsGit
+	HTTP         *DirectoryContentsHTTP
+	Image        *DirectoryContentsImage
+	ImgpkgBundle *DirectoryContentsImgpkgBundle
+	GithubRelease *DirectoryContentsGithubRelease
+	HelmChart    *DirectoryContentsHelmChart
+	Manual       *DirectoryContentsManual
+	Directory    *DirectoryContentsDirectory
+	Inline       *DirectoryContentsInline
+
+	IncludePaths []string
+	ExcludePaths []string
+	IgnorePaths  []string
+	NewRootPath  string
+}
+
+// DirectoryContentsLocalSecretRef points to a Secret that is
+// expected to already be present in the same "namespace" as the
+// vendir invocation (i.e. provided alongside the Config document).
+type DirectoryContentsLocalSecretRef struct {
+	Name string
+}
@@ -0,0 +1,38 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+// DirectoryContentsSigstoreBundle verifies an artifact against an
+// inline Sigstore bundle (media type
+// "application/vnd.dev.sigstore.bundle+json;version=0.3") containing
+// a DSSE envelope, its verification material (certificate chain or
+// public key hint) and a Rekor transparency-log entry with its
+// inclusion proof.
+type DirectoryContentsSigstoreBundle struct {
+	// Inline is the bundle's JSON document, embedded directly in
+	// the Config document.
+	Inline string
+
+	// CertificateIdentity is the expected SAN of the Fulcio
+	// certificate in the bundle's verification material, when that
+	// material is a certificate rather than a bare public key.
+	CertificateIdentity string
+	// CertificateOIDCIssuer is the expected OIDC issuer URL
+	// recorded in that certificate.
+	CertificateOIDCIssuer string
+	// RequireTLog requires that the bundle's Rekor
+	// TransparencyLogEntry inclusion proof be verified, in addition
+	// to the DSSE signature itself.
+	RequireTLog bool
+
+	// FulcioRootsSecretRef points at a Secret whose values are PEM
+	// encoded Fulcio root/intermediate CA certificates, used when
+	// the bundle's verification material is a certificate chain.
+	// Defaults to the public-good Sigstore roots when unset.
+	FulcioRootsSecretRef *DirectoryContentsLocalSecretRef
+	// RekorPublicKeysSecretRef points at a Secret whose values are
+	// PEM encoded Rekor transparency log public keys. It must be set
+	// when RequireTLog is true; verification fails otherwise.
+	RekorPublicKeysSecretRef *DirectoryContentsLocalSecretRef
+}
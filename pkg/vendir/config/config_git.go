@@ -0,0 +1,55 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+// DirectoryContentsGit vendors the contents of a git repository at
+// a particular ref.
+type DirectoryContentsGit struct {
+	URL   string
+	Ref   string
+	Depth int
+
+	SkipInitSubmodules bool
+	LFSSkipSmudge      bool
+
+	Verification *DirectoryContentsGitVerification
+}
+
+// DirectoryContentsGitVerification requires that Ref, when resolved,
+// points at a commit or tag that carries a valid signature before
+// its contents are vendored.
+type DirectoryContentsGitVerification struct {
+	// PublicKeysSecretRef points at a Secret whose values are
+	// armored PGP public keys. The resolved commit or tag must
+	// carry a PGP signature made by one of these keys.
+	PublicKeysSecretRef *DirectoryContentsLocalSecretRef
+
+	// SSHAllowedSignersSecretRef points at a Secret whose values
+	// are OpenSSH allowed_signers files (see ssh-keygen(1) and
+	// PROTOCOL.sshsig). The resolved commit or tag must carry an
+	// SSH signature made by one of the keys listed there.
+	SSHAllowedSignersSecretRef *DirectoryContentsLocalSecretRef
+
+	// AllowedSigners further restricts which identities are
+	// accepted, on top of PublicKeysSecretRef/
+	// SSHAllowedSignersSecretRef establishing that the signature is
+	// made by a trusted key at all. When non-empty, the signing
+	// identity must match at least one entry.
+	AllowedSigners []DirectoryContentsGitAllowedSigner
+
+	// Bundle verifies the resolved ref against an inline Sigstore
+	// bundle instead of (or in addition to) PublicKeysSecretRef/
+	// SSHAllowedSignersSecretRef.
+	Bundle *DirectoryContentsSigstoreBundle
+}
+
+// DirectoryContentsGitAllowedSigner constrains verification to a
+// signer matching KeyID and/or Email (for PGP signatures) or
+// Principal (for SSH signatures). At least one of these fields is
+// expected to be set.
+type DirectoryContentsGitAllowedSigner struct {
+	KeyID     string
+	Email     string
+	Principal string
+}
@@ -0,0 +1,43 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+// DirectoryContentsHTTP vendors a single file (optionally an
+// archive, which is unpacked) downloaded over HTTP(S).
+type DirectoryContentsHTTP struct {
+	URL string
+
+	SHA256 string
+
+	Verification *DirectoryContentsHTTPVerification
+}
+
+// DirectoryContentsHTTPVerification requires that the downloaded
+// artifact carry a valid detached PGP signature and/or match a
+// Sigstore bundle before it is vendored.
+type DirectoryContentsHTTPVerification struct {
+	PGP *DirectoryContentsHTTPPGPVerification
+
+	// Bundle verifies the downloaded artifact against an inline
+	// Sigstore bundle instead of (or in addition to) PGP.
+	Bundle *DirectoryContentsSigstoreBundle
+}
+
+// DirectoryContentsHTTPPGPVerification configures where to find the
+// detached signature for a http source and which keys it must be
+// signed by. At most one of SignatureURL/SignatureSuffix is
+// expected to be set (SignatureURL wins if both are);
+// SignatureSuffix defaults to ".asc" when neither is.
+type DirectoryContentsHTTPPGPVerification struct {
+	// PublicKeysSecretRef points at a Secret whose values are
+	// armored PGP public keys.
+	PublicKeysSecretRef *DirectoryContentsLocalSecretRef
+
+	// SignatureURL is the full URL of the detached signature, when
+	// it does not simply live alongside URL under a known suffix.
+	SignatureURL string
+	// SignatureSuffix is appended to URL to form the signature's
+	// location (e.g. ".asc" or ".sig"). Defaults to ".asc".
+	SignatureSuffix string
+}
@@ -0,0 +1,56 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+// DirectoryContentsImage vendors the unpacked contents of a single
+// OCI image.
+type DirectoryContentsImage struct {
+	URL string
+
+	Verification *DirectoryContentsImageVerification
+}
+
+// DirectoryContentsImageVerification requires that the source's
+// resolved image digest carries a valid cosign signature and/or
+// matches a Sigstore bundle before its contents are vendored.
+type DirectoryContentsImageVerification struct {
+	Cosign *DirectoryContentsCosignVerification
+
+	// Bundle verifies the image against an inline Sigstore bundle
+	// instead of (or in addition to) Cosign.
+	Bundle *DirectoryContentsSigstoreBundle
+}
+
+// DirectoryContentsCosignVerification configures cosign/sigstore
+// signature verification for an image or imgpkgBundle source.
+// Either PublicKeysSecretRef (keyed verification) or
+// CertificateIdentity/CertificateOIDCIssuer (keyless verification)
+// is expected to be set.
+type DirectoryContentsCosignVerification struct {
+	// PublicKeysSecretRef points at a Secret holding a PEM encoded
+	// cosign public key. Only the first PEM block of the Secret's
+	// data is used; further keys are ignored.
+	PublicKeysSecretRef *DirectoryContentsLocalSecretRef
+
+	// CertificateIdentity is the expected SAN of the Fulcio
+	// certificate used for keyless signing (e.g. a GitHub Actions
+	// workflow identity URL).
+	CertificateIdentity string
+	// CertificateOIDCIssuer is the expected OIDC issuer URL
+	// recorded in the Fulcio certificate.
+	CertificateOIDCIssuer string
+	// FulcioRootsSecretRef points at a Secret whose values are PEM
+	// encoded Fulcio root/intermediate CA certificates. Defaults to
+	// the public-good Sigstore roots when unset.
+	FulcioRootsSecretRef *DirectoryContentsLocalSecretRef
+	// RekorPublicKeysSecretRef points at a Secret whose values are
+	// PEM encoded Rekor transparency log public keys. It must be set
+	// when RequireTLog is true; verification fails otherwise.
+	RekorPublicKeysSecretRef *DirectoryContentsLocalSecretRef
+
+	// RequireTLog requires that the signature's inclusion in the
+	// Rekor transparency log be verified, in addition to the
+	// signature itself.
+	RequireTLog bool
+}
@@ -0,0 +1,12 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+// DirectoryContentsImgpkgBundle vendors the unpacked contents of an
+// imgpkg bundle.
+type DirectoryContentsImgpkgBundle struct {
+	Image string
+
+	Verification *DirectoryContentsImageVerification
+}
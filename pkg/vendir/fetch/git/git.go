@@ -0,0 +1,209 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/vmware-tanzu/vendir/pkg/vendir/config"
+	"github.com/vmware-tanzu/vendir/pkg/vendir/fetch/signature"
+	"github.com/vmware-tanzu/vendir/pkg/vendir/fetch/verify"
+)
+
+// Secrets maps a Secret name (as referenced by e.g.
+// verification.publicKeysSecretRef.name) to the concatenation of
+// all of that Secret's data values.
+type Secrets = signature.Secrets
+
+// Git fetches the contents of a single ref of a git repository,
+// optionally verifying that the resolved commit or tag carries a
+// trusted signature before it is vendored.
+type Git struct {
+	opts    config.DirectoryContentsGit
+	secrets Secrets
+}
+
+// NewGit builds a fetcher for the given git source configuration.
+// secrets must contain the data of any Secret referenced from
+// opts.Verification.
+func NewGit(opts config.DirectoryContentsGit, secrets Secrets) *Git {
+	return &Git{opts: opts, secrets: secrets}
+}
+
+// Retrieve clones t.opts.URL into dstPath, checks out t.opts.Ref,
+// verifies it (if verification is configured) and returns the
+// resolved commit SHA.
+func (t *Git) Retrieve(dstPath string) (string, error) {
+	if err := t.run([]string{"clone", t.opts.URL, dstPath}, ""); err != nil {
+		return "", fmt.Errorf("Cloning git repository '%s': %s", t.opts.URL, err)
+	}
+
+	if err := t.run([]string{"checkout", t.opts.Ref}, dstPath); err != nil {
+		return "", fmt.Errorf("Checking out ref '%s': %s", t.opts.Ref, err)
+	}
+
+	sha, err := t.runOut([]string{"rev-parse", "HEAD"}, dstPath)
+	if err != nil {
+		return "", fmt.Errorf("Determining resolved HEAD: %s", err)
+	}
+	sha = strings.TrimSpace(sha)
+
+	if t.opts.Verification != nil {
+		if err := t.verify(dstPath, sha); err != nil {
+			return "", fmt.Errorf("Verifying ref '%s' (resolved to '%s'): %s", t.opts.Ref, sha, err)
+		}
+	}
+
+	return sha, nil
+}
+
+// verify checks sha against t.opts.Verification.Bundle (if set) and,
+// independently, against a PGP or SSH signature extracted from the
+// resolved commit or tag object (if publicKeysSecretRef or
+// sshAllowedSignersSecretRef is set).
+func (t *Git) verify(dstPath, sha string) error {
+	v := t.opts.Verification
+
+	if v.Bundle != nil {
+		verifier := verify.NewSigstoreBundleVerifier(*v.Bundle, t.secrets)
+		meta := map[string]string{verify.MetaDigest: sha}
+		if err := verifier.Verify(context.Background(), t.opts.URL, nil, meta); err != nil {
+			return err
+		}
+	}
+
+	if v.PublicKeysSecretRef == nil && v.SSHAllowedSignersSecretRef == nil {
+		if v.Bundle == nil {
+			return fmt.Errorf("Expected publicKeysSecretRef, sshAllowedSignersSecretRef or bundle to be set")
+		}
+		return nil
+	}
+
+	return t.verifySignature(dstPath, sha)
+}
+
+// verifySignature extracts the signed payload and detached signature
+// from the resolved commit or tag object and runs it through the
+// verifier selected by t.opts.Verification.
+func (t *Git) verifySignature(dstPath, sha string) error {
+	objType, err := t.runOut([]string{"cat-file", "-t", t.opts.Ref}, dstPath)
+	if err != nil {
+		return fmt.Errorf("Determining object type of ref '%s': %s", t.opts.Ref, err)
+	}
+	objType = strings.TrimSpace(objType)
+
+	obj := sha
+	if objType == "tag" {
+		obj = t.opts.Ref
+	}
+
+	raw, err := t.runOut([]string{"cat-file", objType, obj}, dstPath)
+	if err != nil {
+		return fmt.Errorf("Reading %s object '%s': %s", objType, obj, err)
+	}
+
+	sigHeader := "gpgsig "
+	if objType == "tag" {
+		sigHeader = ""
+	}
+
+	payload, sig, err := splitSignedObject(raw, sigHeader)
+	if err != nil {
+		if objType == "tag" {
+			return fmt.Errorf("Expected to find tag signature: %s", err)
+		}
+		return fmt.Errorf("Expected to find commit signature: %s", err)
+	}
+
+	verifier, err := t.selectVerifier(sig)
+	if err != nil {
+		return err
+	}
+
+	meta := map[string]string{verify.MetaSignature: sig}
+	return verifier.Verify(context.Background(), t.opts.URL, strings.NewReader(payload), meta)
+}
+
+// selectVerifier picks the Verifier matching how sig is formatted:
+// an SSHSIG armored block goes to the SSH allowed_signers verifier,
+// everything else (armored or binary PGP) goes to the PGP keyring
+// verifier.
+func (t *Git) selectVerifier(sig string) (verify.Verifier, error) {
+	v := t.opts.Verification
+
+	if strings.Contains(sig, "BEGIN SSH SIGNATURE") {
+		if v.SSHAllowedSignersSecretRef == nil {
+			return nil, fmt.Errorf("Expected sshAllowedSignersSecretRef to be set for SSH verification")
+		}
+		return verify.NewSSHAllowedSignersVerifier(v.SSHAllowedSignersSecretRef.Name, t.secrets, v.AllowedSigners), nil
+	}
+
+	if v.PublicKeysSecretRef == nil {
+		return nil, fmt.Errorf("Expected publicKeysSecretRef to be set for PGP verification")
+	}
+	return verify.NewPGPKeyringVerifier(v.PublicKeysSecretRef.Name, t.secrets, v.AllowedSigners), nil
+}
+
+func (t *Git) run(args []string, dir string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s (stderr: %s)", err, stderr.String())
+	}
+	return nil
+}
+
+func (t *Git) runOut(args []string, dir string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s (stderr: %s)", err, stderr.String())
+	}
+	return stdout.String(), nil
+}
+
+// splitSignedObject separates a raw commit or tag object into its
+// signed payload and the detached signature embedded in it. For
+// commits the signature lives in a "gpgsig " header line (whose
+// continuation lines are indented with a single space); for tags it
+// is appended after the tag message, introduced by the signature's
+// own "-----BEGIN ..." marker.
+func splitSignedObject(raw, header string) (string, string, error) {
+	if header != "" {
+		lines := strings.Split(raw, "\n")
+		var payload, sig []string
+		var inSig bool
+		for _, line := range lines {
+			switch {
+			case strings.HasPrefix(line, header):
+				inSig = true
+				sig = append(sig, strings.TrimPrefix(line, header))
+			case inSig && strings.HasPrefix(line, " "):
+				sig = append(sig, strings.TrimPrefix(line, " "))
+			default:
+				inSig = false
+				payload = append(payload, line)
+			}
+		}
+		if len(sig) == 0 {
+			return "", "", fmt.Errorf("Expected to find section 'PGP SIGNATURE', but did not")
+		}
+		return strings.Join(payload, "\n"), strings.Join(sig, "\n") + "\n", nil
+	}
+
+	idx := strings.Index(raw, "-----BEGIN")
+	if idx == -1 {
+		return "", "", fmt.Errorf("Expected to find section 'PGP SIGNATURE', but did not")
+	}
+	return raw[:idx], raw[idx:], nil
+}
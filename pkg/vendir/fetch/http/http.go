@@ -0,0 +1,106 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/vmware-tanzu/vendir/pkg/vendir/config"
+	"github.com/vmware-tanzu/vendir/pkg/vendir/fetch/signature"
+	"github.com/vmware-tanzu/vendir/pkg/vendir/fetch/verify"
+)
+
+const defaultSignatureSuffix = ".asc"
+
+// HTTP downloads a single file, optionally verifying a
+// upstream-provided detached PGP signature (or Sigstore bundle)
+// before it is vendored.
+type HTTP struct {
+	opts    config.DirectoryContentsHTTP
+	secrets signature.Secrets
+}
+
+// NewHTTP builds a fetcher for the given http source configuration.
+// secrets must contain the data of any Secret referenced from
+// opts.Verification.
+func NewHTTP(opts config.DirectoryContentsHTTP, secrets signature.Secrets) *HTTP {
+	return &HTTP{opts: opts, secrets: secrets}
+}
+
+// Retrieve downloads t.opts.URL into dstPath, verifying it first if
+// t.opts.Verification is set.
+func (t *HTTP) Retrieve(dstPath string) error {
+	artifact, err := download(t.opts.URL)
+	if err != nil {
+		return fmt.Errorf("Downloading '%s': %s", t.opts.URL, err)
+	}
+
+	if err := t.verify(artifact); err != nil {
+		return fmt.Errorf("Verifying '%s': %s", t.opts.URL, err)
+	}
+
+	dst := filepath.Join(dstPath, filepath.Base(t.opts.URL))
+	return os.WriteFile(dst, artifact, 0644)
+}
+
+func (t *HTTP) verify(artifact []byte) error {
+	if t.opts.Verification == nil {
+		return nil
+	}
+
+	if opts := t.opts.Verification.PGP; opts != nil {
+		if opts.PublicKeysSecretRef == nil {
+			return fmt.Errorf("Expected publicKeysSecretRef to be set for PGP verification")
+		}
+
+		sigURL := opts.SignatureURL
+		if sigURL == "" {
+			suffix := opts.SignatureSuffix
+			if suffix == "" {
+				suffix = defaultSignatureSuffix
+			}
+			sigURL = t.opts.URL + suffix
+		}
+
+		sig, err := download(sigURL)
+		if err != nil {
+			return fmt.Errorf("Downloading detached signature '%s': %s", sigURL, err)
+		}
+
+		verifier := verify.NewPGPKeyringVerifier(opts.PublicKeysSecretRef.Name, t.secrets, nil)
+		meta := map[string]string{verify.MetaSignature: string(sig)}
+		if err := verifier.Verify(context.Background(), t.opts.URL, bytes.NewReader(artifact), meta); err != nil {
+			return err
+		}
+	}
+
+	if bundle := t.opts.Verification.Bundle; bundle != nil {
+		verifier := verify.NewSigstoreBundleVerifier(*bundle, t.secrets)
+		if err := verifier.Verify(context.Background(), t.opts.URL, bytes.NewReader(artifact), nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func download(url string) ([]byte, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Expected HTTP status 200, got %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
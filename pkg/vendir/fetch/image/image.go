@@ -0,0 +1,89 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+
+	"github.com/vmware-tanzu/vendir/pkg/vendir/config"
+	"github.com/vmware-tanzu/vendir/pkg/vendir/fetch/cosign"
+	"github.com/vmware-tanzu/vendir/pkg/vendir/fetch/verify"
+)
+
+// Image fetches and unpacks the contents of a single OCI image,
+// optionally verifying its cosign signature first.
+type Image struct {
+	opts    config.DirectoryContentsImage
+	secrets cosign.Secrets
+}
+
+// NewImage builds a fetcher for the given image source
+// configuration. secrets must contain the data of any Secret
+// referenced from opts.Verification.
+func NewImage(opts config.DirectoryContentsImage, secrets cosign.Secrets) *Image {
+	return &Image{opts: opts, secrets: secrets}
+}
+
+// Retrieve verifies (if configured) and exports the flattened
+// filesystem of t.opts.URL into dstPath, returning the resolved
+// image digest.
+func (t *Image) Retrieve(dstPath string) (string, error) {
+	img, err := crane.Pull(t.opts.URL)
+	if err != nil {
+		return "", fmt.Errorf("Pulling image '%s': %s", t.opts.URL, err)
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		return "", fmt.Errorf("Determining digest of image '%s': %s", t.opts.URL, err)
+	}
+
+	meta := map[string]string{verify.MetaDigest: digest.String()}
+	for _, verifier := range t.verifiers() {
+		if err := verifier.Verify(context.Background(), t.opts.URL, nil, meta); err != nil {
+			return "", fmt.Errorf("Verifying image '%s': %s", t.opts.URL, err)
+		}
+	}
+
+	tarFile, err := os.CreateTemp("", "vendir-image-export")
+	if err != nil {
+		return "", fmt.Errorf("Creating temporary export file: %s", err)
+	}
+	defer os.Remove(tarFile.Name())
+	defer tarFile.Close()
+
+	if err := crane.Export(img, tarFile); err != nil {
+		return "", fmt.Errorf("Exporting image '%s': %s", t.opts.URL, err)
+	}
+	if _, err := tarFile.Seek(0, 0); err != nil {
+		return "", fmt.Errorf("Rewinding export file: %s", err)
+	}
+
+	if err := extractTar(tarFile, dstPath); err != nil {
+		return "", fmt.Errorf("Extracting image '%s': %s", t.opts.URL, err)
+	}
+
+	return digest.String(), nil
+}
+
+// verifiers returns the Verifiers selected by t.opts.Verification,
+// in the order they should be checked.
+func (t *Image) verifiers() []verify.Verifier {
+	if t.opts.Verification == nil {
+		return nil
+	}
+
+	var verifiers []verify.Verifier
+	if t.opts.Verification.Cosign != nil {
+		verifiers = append(verifiers, verify.NewCosignKeylessVerifier(*t.opts.Verification.Cosign, t.secrets))
+	}
+	if t.opts.Verification.Bundle != nil {
+		verifiers = append(verifiers, verify.NewSigstoreBundleVerifier(*t.opts.Verification.Bundle, t.secrets))
+	}
+	return verifiers
+}
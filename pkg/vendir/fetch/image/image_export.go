@@ -0,0 +1,67 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// extractTar unpacks a tar stream (as produced by crane.Export)
+// into dstPath.
+func extractTar(r io.Reader, dstPath string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		path, err := safeJoin(dstPath, hdr.Name)
+		if err != nil {
+			return err
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(hdr.Mode))
+			if err != nil {
+				return err
+			}
+			_, copyErr := io.Copy(f, tr)
+			f.Close()
+			if copyErr != nil {
+				return copyErr
+			}
+		default:
+			// symlinks, devices, etc. are not expected in vendored
+			// image contents and are skipped.
+		}
+	}
+}
+
+// safeJoin joins dstPath and name, rejecting any entry (e.g. one
+// containing "../") whose resolved path would escape dstPath.
+func safeJoin(dstPath, name string) (string, error) {
+	cleanDst := filepath.Clean(dstPath)
+	path := filepath.Join(cleanDst, name)
+	if path != cleanDst && !strings.HasPrefix(path, cleanDst+string(filepath.Separator)) {
+		return "", fmt.Errorf("Tar entry '%s' escapes destination directory", name)
+	}
+	return path, nil
+}
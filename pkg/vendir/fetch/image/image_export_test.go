@@ -0,0 +1,62 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractTarRejectsPathEscape(t *testing.T) {
+	dstPath, err := os.MkdirTemp("", "vendir-image-extract-tar-slip")
+	require.NoError(t, err)
+	defer os.RemoveAll(dstPath)
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	content := []byte("malicious")
+	require.NoError(t, tw.WriteHeader(&tar.Header{
+		Name: "../../etc/cron.d/x",
+		Mode: 0644,
+		Size: int64(len(content)),
+	}))
+	_, err = tw.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+
+	err = extractTar(&buf, dstPath)
+	require.ErrorContains(t, err, "escapes destination directory")
+
+	_, statErr := os.Stat(filepath.Join(filepath.Dir(filepath.Dir(dstPath)), "etc/cron.d/x"))
+	require.Error(t, statErr, "tar entry must not have been written outside dstPath")
+}
+
+func TestExtractTarWritesRegularFilesAndDirs(t *testing.T) {
+	dstPath, err := os.MkdirTemp("", "vendir-image-extract-tar-ok")
+	require.NoError(t, err)
+	defer os.RemoveAll(dstPath)
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	content := []byte("hello\n")
+	require.NoError(t, tw.WriteHeader(&tar.Header{
+		Name: "nested/dir/file.txt",
+		Mode: 0644,
+		Size: int64(len(content)),
+	}))
+	_, err = tw.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+
+	require.NoError(t, extractTar(&buf, dstPath))
+
+	got, err := os.ReadFile(filepath.Join(dstPath, "nested/dir/file.txt"))
+	require.NoError(t, err)
+	require.Equal(t, content, got)
+}
@@ -0,0 +1,76 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package imgpkgbundle
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+
+	"github.com/vmware-tanzu/vendir/pkg/vendir/config"
+	"github.com/vmware-tanzu/vendir/pkg/vendir/fetch/cosign"
+	"github.com/vmware-tanzu/vendir/pkg/vendir/fetch/verify"
+)
+
+// ImgpkgBundle fetches and unpacks the contents of an imgpkg
+// bundle, optionally verifying its signature first. An imgpkg
+// bundle is itself an OCI image, so it is verified the same way a
+// plain image source is.
+type ImgpkgBundle struct {
+	opts    config.DirectoryContentsImgpkgBundle
+	secrets cosign.Secrets
+}
+
+// NewImgpkgBundle builds a fetcher for the given imgpkgBundle
+// source configuration. secrets must contain the data of any
+// Secret referenced from opts.Verification.
+func NewImgpkgBundle(opts config.DirectoryContentsImgpkgBundle, secrets cosign.Secrets) *ImgpkgBundle {
+	return &ImgpkgBundle{opts: opts, secrets: secrets}
+}
+
+// Retrieve verifies (if configured) t.opts.Image and then defers to
+// the imgpkg CLI to pull and unpack the bundle into dstPath.
+func (t *ImgpkgBundle) Retrieve(dstPath string) (string, error) {
+	verifiers := t.verifiers()
+	if len(verifiers) > 0 {
+		digest, err := crane.Digest(t.opts.Image)
+		if err != nil {
+			return "", fmt.Errorf("Determining digest of imgpkg bundle '%s': %s", t.opts.Image, err)
+		}
+
+		meta := map[string]string{verify.MetaDigest: digest}
+		for _, verifier := range verifiers {
+			if err := verifier.Verify(context.Background(), t.opts.Image, nil, meta); err != nil {
+				return "", fmt.Errorf("Verifying imgpkg bundle '%s': %s", t.opts.Image, err)
+			}
+		}
+	}
+
+	cmd := exec.Command("imgpkg", "pull", "-b", t.opts.Image, "-o", dstPath)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("Unpacking imgpkg bundle '%s': %s (output: '%s')", t.opts.Image, err, out)
+	}
+
+	return t.opts.Image, nil
+}
+
+// verifiers returns the Verifiers selected by t.opts.Verification,
+// in the order they should be checked.
+func (t *ImgpkgBundle) verifiers() []verify.Verifier {
+	if t.opts.Verification == nil {
+		return nil
+	}
+
+	var verifiers []verify.Verifier
+	if t.opts.Verification.Cosign != nil {
+		verifiers = append(verifiers, verify.NewCosignKeylessVerifier(*t.opts.Verification.Cosign, t.secrets))
+	}
+	if t.opts.Verification.Bundle != nil {
+		verifiers = append(verifiers, verify.NewSigstoreBundleVerifier(*t.opts.Verification.Bundle, t.secrets))
+	}
+	return verifiers
+}
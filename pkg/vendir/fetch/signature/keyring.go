@@ -0,0 +1,60 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package signature holds the PGP keyring handling shared by every
+// content type that supports detached-signature verification (git,
+// http).
+package signature
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+)
+
+// Secrets maps a Secret name to the concatenation of that Secret's
+// data values.
+type Secrets map[string]string
+
+// BuildPGPKeyring parses the armored PGP public keys stored under
+// secretName into a keyring suitable for
+// openpgp.CheckArmoredDetachedSignature.
+func BuildPGPKeyring(secretName string, secrets Secrets) (openpgp.EntityList, error) {
+	data, found := secrets[secretName]
+	if !found {
+		return nil, fmt.Errorf("Expected to find secret '%s' for verification", secretName)
+	}
+
+	// openpgp.ReadArmoredKeyRing only reads the first armored block,
+	// so each block in data is parsed on its own.
+	const armorHeader = "-----BEGIN PGP PUBLIC KEY BLOCK-----"
+	var keyring openpgp.EntityList
+	for _, block := range strings.Split(data, armorHeader)[1:] {
+		entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armorHeader + block))
+		if err != nil {
+			return nil, fmt.Errorf("Parsing public keys from secret '%s': %s", secretName, err)
+		}
+		keyring = append(keyring, entities...)
+	}
+	if len(keyring) == 0 {
+		return nil, fmt.Errorf("Expected to find at least one armored PGP public key in secret '%s'", secretName)
+	}
+	return keyring, nil
+}
+
+// CheckDetachedSignature verifies that sig is a valid detached PGP
+// signature (armored or binary) of payload, made by a key in
+// keyring, and returns the signing entity.
+func CheckDetachedSignature(keyring openpgp.EntityList, payload io.Reader, sig io.Reader) (*openpgp.Entity, error) {
+	sigBytes, err := io.ReadAll(sig)
+	if err != nil {
+		return nil, fmt.Errorf("Reading signature: %s", err)
+	}
+
+	if strings.Contains(string(sigBytes), "-----BEGIN PGP SIGNATURE-----") {
+		return openpgp.CheckArmoredDetachedSignature(keyring, payload, strings.NewReader(string(sigBytes)), nil)
+	}
+	return openpgp.CheckDetachedSignature(keyring, payload, strings.NewReader(string(sigBytes)), nil)
+}
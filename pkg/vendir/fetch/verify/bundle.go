@@ -0,0 +1,126 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package verify
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+
+	sigstorebundle "github.com/sigstore/sigstore-go/pkg/bundle"
+	sigstoreroot "github.com/sigstore/sigstore-go/pkg/root"
+	sigstoreverify "github.com/sigstore/sigstore-go/pkg/verify"
+
+	"github.com/vmware-tanzu/vendir/pkg/vendir/config"
+)
+
+// sigstoreBundleVerifier checks an artifact against an inline
+// Sigstore bundle: the DSSE envelope's signature, its subject
+// digest against the artifact actually fetched, the Fulcio
+// certificate chain/identity (when the verification material is a
+// certificate) and the Rekor transparency-log inclusion proof.
+type sigstoreBundleVerifier struct {
+	bundle  config.DirectoryContentsSigstoreBundle
+	secrets map[string]string
+}
+
+// NewSigstoreBundleVerifier builds a Verifier that checks an
+// artifact against bundle.Inline, per bundle's
+// CertificateIdentity/CertificateOIDCIssuer/RequireTLog settings.
+// secrets must contain the data of any Secret referenced from
+// bundle (FulcioRootsSecretRef/RekorPublicKeysSecretRef).
+func NewSigstoreBundleVerifier(bundle config.DirectoryContentsSigstoreBundle, secrets map[string]string) Verifier {
+	return &sigstoreBundleVerifier{bundle: bundle, secrets: secrets}
+}
+
+func (v *sigstoreBundleVerifier) Verify(_ context.Context, artifactRef string, payload io.Reader, meta map[string]string) error {
+	if v.bundle.RequireTLog && v.bundle.RekorPublicKeysSecretRef == nil {
+		return fmt.Errorf("Expected rekorPublicKeysSecretRef to be set when requireTLog is true")
+	}
+
+	digest, err := v.resolveDigest(payload, meta)
+	if err != nil {
+		return err
+	}
+
+	b, err := sigstorebundle.LoadJSONFromBytes([]byte(v.bundle.Inline))
+	if err != nil {
+		return fmt.Errorf("Parsing sigstore bundle for '%s': %s", artifactRef, err)
+	}
+
+	trustedRoot, err := v.resolveSecret(v.bundle.FulcioRootsSecretRef)
+	if err != nil {
+		return err
+	}
+	rekorPubKeys, err := v.resolveSecret(v.bundle.RekorPublicKeysSecretRef)
+	if err != nil {
+		return err
+	}
+
+	trustedMaterial, err := sigstoreroot.NewTrustedRootFromBytes(trustedRoot, rekorPubKeys)
+	if err != nil {
+		return fmt.Errorf("Building trusted root material: %s", err)
+	}
+
+	verifierOpts := []sigstoreverify.VerifierOption{sigstoreverify.WithSignedCertificateTimestamps(1)}
+	if v.bundle.RequireTLog {
+		// Verify the bundle's own embedded TransparencyLogEntry
+		// inclusion proof against trustedMaterial's Rekor keys,
+		// rather than fetching a live checkpoint from the public
+		// Sigstore Rekor service.
+		verifierOpts = append(verifierOpts, sigstoreverify.WithTransparencyLog(1))
+	}
+
+	verifier, err := sigstoreverify.NewVerifier(trustedMaterial, verifierOpts...)
+	if err != nil {
+		return fmt.Errorf("Building sigstore bundle verifier: %s", err)
+	}
+
+	policyOpts := []sigstoreverify.PolicyOption{
+		sigstoreverify.WithArtifactDigest("sha256", digest),
+	}
+	if v.bundle.CertificateIdentity != "" || v.bundle.CertificateOIDCIssuer != "" {
+		policyOpts = append(policyOpts, sigstoreverify.WithCertificateIdentity(sigstoreverify.CertificateIdentity{
+			SubjectAlternativeName: v.bundle.CertificateIdentity,
+			Issuer:                 v.bundle.CertificateOIDCIssuer,
+		}))
+	}
+
+	_, err = verifier.Verify(b, sigstoreverify.NewPolicy(policyOpts...))
+	if err != nil {
+		return fmt.Errorf("Verifying sigstore bundle for '%s': %s", artifactRef, err)
+	}
+	return nil
+}
+
+// resolveSecret returns secrets[ref.Name], or nil (meaning "use the
+// public-good Sigstore defaults") when ref is unset.
+func (v *sigstoreBundleVerifier) resolveSecret(ref *config.DirectoryContentsLocalSecretRef) ([]byte, error) {
+	if ref == nil {
+		return nil, nil
+	}
+	data, found := v.secrets[ref.Name]
+	if !found {
+		return nil, fmt.Errorf("Expected to find secret '%s' for bundle verification", ref.Name)
+	}
+	return []byte(data), nil
+}
+
+func (v *sigstoreBundleVerifier) resolveDigest(payload io.Reader, meta map[string]string) (string, error) {
+	if digest, found := meta[MetaDigest]; found {
+		return strings.TrimPrefix(digest, "sha256:"), nil
+	}
+	if payload == nil {
+		return "", fmt.Errorf("Expected either %s in verifier metadata or a readable payload", MetaDigest)
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, payload); err != nil {
+		return "", fmt.Errorf("Hashing payload: %s", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package verify
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/vendir/pkg/vendir/config"
+)
+
+// TestSigstoreBundleVerifierSecretResolution exercises the
+// sigstoreBundleVerifier's resolution of
+// FulcioRootsSecretRef/RekorPublicKeysSecretRef/RequireTLog, which
+// the hardcoded-empties bug previously skipped entirely. A full
+// successful verification requires a real Sigstore bundle signed by
+// Fulcio/Rekor, which is outside the scope of a unit test, so this
+// exercises the verifier's internals directly, and calls Verify only
+// with inputs that are rejected before any signature is checked.
+func TestSigstoreBundleVerifierSecretResolution(t *testing.T) {
+	t.Run("resolveSecret errors when the referenced secret is missing", func(t *testing.T) {
+		v := &sigstoreBundleVerifier{secrets: map[string]string{}}
+		_, err := v.resolveSecret(&config.DirectoryContentsLocalSecretRef{Name: "fulcio-roots"})
+		require.ErrorContains(t, err, "fulcio-roots")
+	})
+
+	t.Run("resolveSecret returns the secret data when present", func(t *testing.T) {
+		v := &sigstoreBundleVerifier{secrets: map[string]string{"rekor-keys": "pem-bytes"}}
+		data, err := v.resolveSecret(&config.DirectoryContentsLocalSecretRef{Name: "rekor-keys"})
+		require.NoError(t, err)
+		require.Equal(t, "pem-bytes", string(data))
+	})
+
+	t.Run("resolveSecret returns nil, nil for an unset ref", func(t *testing.T) {
+		v := &sigstoreBundleVerifier{secrets: map[string]string{}}
+		data, err := v.resolveSecret(nil)
+		require.NoError(t, err)
+		require.Nil(t, data)
+	})
+
+	t.Run("requireTLog without rekorPublicKeysSecretRef is rejected", func(t *testing.T) {
+		bundle := config.DirectoryContentsSigstoreBundle{
+			Inline:      "not a real bundle",
+			RequireTLog: true,
+		}
+		v := NewSigstoreBundleVerifier(bundle, map[string]string{})
+		err := v.Verify(context.Background(), "artifact", strings.NewReader("payload"), nil)
+		require.ErrorContains(t, err, "rekorPublicKeysSecretRef")
+	})
+
+	t.Run("resolveDigest prefers MetaDigest over hashing the payload", func(t *testing.T) {
+		v := &sigstoreBundleVerifier{}
+		digest, err := v.resolveDigest(strings.NewReader("ignored"), map[string]string{MetaDigest: "sha256:deadbeef"})
+		require.NoError(t, err)
+		require.Equal(t, "deadbeef", digest)
+	})
+
+	t.Run("resolveDigest hashes the payload when no metadata digest is given", func(t *testing.T) {
+		v := &sigstoreBundleVerifier{}
+		digest, err := v.resolveDigest(strings.NewReader("hello\n"), nil)
+		require.NoError(t, err)
+		require.Len(t, digest, 64)
+	})
+
+	t.Run("malformed inline bundle surfaces a parse error", func(t *testing.T) {
+		bundle := config.DirectoryContentsSigstoreBundle{Inline: "not json"}
+		v := NewSigstoreBundleVerifier(bundle, map[string]string{})
+		err := v.Verify(context.Background(), "artifact", strings.NewReader("payload"), nil)
+		require.ErrorContains(t, err, "Parsing sigstore bundle")
+	})
+
+	t.Run("requireTLog with rekorPublicKeysSecretRef set proceeds past the config guard", func(t *testing.T) {
+		// This does not carry a valid bundle, so it still fails
+		// further down the chain (when parsing the inline bundle,
+		// before any sigstoreverify options are built), but it must
+		// not be rejected for the requireTLog/rekorPublicKeysSecretRef
+		// guard itself.
+		bundle := config.DirectoryContentsSigstoreBundle{
+			Inline:                   "not a real bundle",
+			RequireTLog:              true,
+			RekorPublicKeysSecretRef: &config.DirectoryContentsLocalSecretRef{Name: "rekor-keys"},
+		}
+		v := NewSigstoreBundleVerifier(bundle, map[string]string{"rekor-keys": "not-a-real-key"})
+		err := v.Verify(context.Background(), "artifact", strings.NewReader("payload"), nil)
+		require.Error(t, err)
+		require.NotContains(t, err.Error(), "rekorPublicKeysSecretRef")
+	})
+}
@@ -0,0 +1,33 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package verify
+
+import (
+	"context"
+	"io"
+
+	"github.com/vmware-tanzu/vendir/pkg/vendir/config"
+	"github.com/vmware-tanzu/vendir/pkg/vendir/fetch/cosign"
+)
+
+// cosignKeylessVerifier checks that artifactRef carries a cosign
+// signature satisfying opts, either by a configured public key or
+// (keyless) backed by a Fulcio certificate matching the configured
+// identity/issuer; see cosign.VerifyRef. It ignores payload/meta: cosign
+// locates signatures itself via the registry, keyed off
+// artifactRef's resolved digest.
+type cosignKeylessVerifier struct {
+	opts    config.DirectoryContentsCosignVerification
+	secrets cosign.Secrets
+}
+
+// NewCosignKeylessVerifier builds a Verifier performing cosign
+// verification (keyed or keyless), as configured by opts.
+func NewCosignKeylessVerifier(opts config.DirectoryContentsCosignVerification, secrets cosign.Secrets) Verifier {
+	return &cosignKeylessVerifier{opts: opts, secrets: secrets}
+}
+
+func (v *cosignKeylessVerifier) Verify(ctx context.Context, artifactRef string, _ io.Reader, _ map[string]string) error {
+	return cosign.VerifyRef(ctx, artifactRef, v.opts, v.secrets)
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package verify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+
+	"github.com/vmware-tanzu/vendir/pkg/vendir/config"
+	"github.com/vmware-tanzu/vendir/pkg/vendir/fetch/signature"
+)
+
+// pgpKeyringVerifier checks a detached PGP signature (provided via
+// MetaSignature) against a keyring, and optionally restricts which
+// identities within that keyring are accepted.
+type pgpKeyringVerifier struct {
+	keyringSecret  string
+	secrets        signature.Secrets
+	allowedSigners []config.DirectoryContentsGitAllowedSigner
+}
+
+// NewPGPKeyringVerifier builds a Verifier backed by the armored PGP
+// public keys in secrets[keyringSecret]. allowedSigners, when
+// non-empty, further restricts accepted signers by key ID/email.
+func NewPGPKeyringVerifier(keyringSecret string, secrets signature.Secrets, allowedSigners []config.DirectoryContentsGitAllowedSigner) Verifier {
+	return &pgpKeyringVerifier{keyringSecret: keyringSecret, secrets: secrets, allowedSigners: allowedSigners}
+}
+
+func (v *pgpKeyringVerifier) Verify(_ context.Context, _ string, payload io.Reader, meta map[string]string) error {
+	sig, found := meta[MetaSignature]
+	if !found {
+		return fmt.Errorf("Expected %s to be set in verifier metadata", MetaSignature)
+	}
+
+	keyring, err := signature.BuildPGPKeyring(v.keyringSecret, v.secrets)
+	if err != nil {
+		return err
+	}
+
+	entity, err := signature.CheckDetachedSignature(keyring, payload, strings.NewReader(sig))
+	if err != nil {
+		return err
+	}
+
+	return checkAllowedPGPSigner(entity, v.allowedSigners)
+}
+
+func checkAllowedPGPSigner(entity *openpgp.Entity, allowedSigners []config.DirectoryContentsGitAllowedSigner) error {
+	if len(allowedSigners) == 0 {
+		return nil
+	}
+
+	keyID := fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint)
+	var emails []string
+	for _, ident := range entity.Identities {
+		if ident.UserId != nil && ident.UserId.Email != "" {
+			emails = append(emails, ident.UserId.Email)
+		}
+	}
+
+	for _, allowed := range allowedSigners {
+		if allowed.KeyID == "" && allowed.Email == "" {
+			// An entry with neither set (e.g. one meant only to
+			// restrict SSH signers via Principal) matches no PGP
+			// signer, rather than acting as a wildcard.
+			continue
+		}
+		if allowed.KeyID != "" && !strings.HasSuffix(keyID, strings.ToUpper(allowed.KeyID)) {
+			continue
+		}
+		if allowed.Email != "" {
+			var emailMatches bool
+			for _, email := range emails {
+				if email == allowed.Email {
+					emailMatches = true
+					break
+				}
+			}
+			if !emailMatches {
+				continue
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("signature made by %s not in allowedSigners", keyID)
+}
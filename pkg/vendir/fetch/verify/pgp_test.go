@@ -0,0 +1,94 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package verify
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/vendir/pkg/vendir/config"
+)
+
+// TestPGPKeyringVerifier exercises pgpKeyringVerifier purely through
+// the Verifier interface returned by its exported constructor
+// (NewPGPKeyringVerifier), rather than through the git fetcher's
+// gpgsig/tag-object extraction.
+func TestPGPKeyringVerifier(t *testing.T) {
+	trusted := newPGPEntity(t, "Trusted Signer", "trusted@example.com")
+	stranger := newPGPEntity(t, "Stranger", "stranger@example.com")
+
+	payload := "artifact contents\n"
+	sig := signPGP(t, trusted, payload)
+
+	secrets := map[string]string{"pubs": armorPublicKey(t, trusted)}
+
+	t.Run("signed by a key in the keyring", func(t *testing.T) {
+		v := NewPGPKeyringVerifier("pubs", secrets, nil)
+		err := v.Verify(context.Background(), "artifact", strings.NewReader(payload), map[string]string{MetaSignature: sig})
+		require.NoError(t, err)
+	})
+
+	t.Run("signed by a key not in the keyring", func(t *testing.T) {
+		strangerSig := signPGP(t, stranger, payload)
+		v := NewPGPKeyringVerifier("pubs", secrets, nil)
+		err := v.Verify(context.Background(), "artifact", strings.NewReader(payload), map[string]string{MetaSignature: strangerSig})
+		require.Error(t, err)
+	})
+
+	t.Run("trusted key but identity not in allowedSigners", func(t *testing.T) {
+		v := NewPGPKeyringVerifier("pubs", secrets, []config.DirectoryContentsGitAllowedSigner{{Email: "someone-else@example.com"}})
+		err := v.Verify(context.Background(), "artifact", strings.NewReader(payload), map[string]string{MetaSignature: sig})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not in allowedSigners")
+	})
+
+	t.Run("trusted key with matching identity", func(t *testing.T) {
+		v := NewPGPKeyringVerifier("pubs", secrets, []config.DirectoryContentsGitAllowedSigner{{Email: "trusted@example.com"}})
+		err := v.Verify(context.Background(), "artifact", strings.NewReader(payload), map[string]string{MetaSignature: sig})
+		require.NoError(t, err)
+	})
+
+	t.Run("allowedSigners entry with only a principal matches no PGP signer", func(t *testing.T) {
+		v := NewPGPKeyringVerifier("pubs", secrets, []config.DirectoryContentsGitAllowedSigner{{Principal: "git@example.com"}})
+		err := v.Verify(context.Background(), "artifact", strings.NewReader(payload), map[string]string{MetaSignature: sig})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not in allowedSigners")
+	})
+
+	t.Run("missing metadata signature", func(t *testing.T) {
+		v := NewPGPKeyringVerifier("pubs", secrets, nil)
+		err := v.Verify(context.Background(), "artifact", strings.NewReader(payload), nil)
+		require.Error(t, err)
+	})
+}
+
+func newPGPEntity(t *testing.T, name, email string) *openpgp.Entity {
+	t.Helper()
+	entity, err := openpgp.NewEntity(name, "", email, nil)
+	require.NoError(t, err)
+	return entity
+}
+
+func armorPublicKey(t *testing.T, entity *openpgp.Entity) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(w))
+	require.NoError(t, w.Close())
+	return buf.String()
+}
+
+func signPGP(t *testing.T, entity *openpgp.Entity, payload string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, openpgp.ArmoredDetachSign(&buf, entity, strings.NewReader(payload), nil))
+	return buf.String()
+}
@@ -0,0 +1,253 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package verify
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/vmware-tanzu/vendir/pkg/vendir/config"
+)
+
+const (
+	sshSigMagic     = "SSHSIG"
+	sshSigNamespace = "git"
+)
+
+// sshAllowedSignersVerifier checks an armored SSH signature
+// (PROTOCOL.sshsig, provided via MetaSignature) against an OpenSSH
+// allowed_signers file, and optionally restricts which principals
+// are accepted.
+type sshAllowedSignersVerifier struct {
+	allowedSignersSecret string
+	secrets              map[string]string
+	allowedSigners       []config.DirectoryContentsGitAllowedSigner
+}
+
+// NewSSHAllowedSignersVerifier builds a Verifier backed by the
+// allowed_signers file in secrets[allowedSignersSecret].
+// allowedSigners, when non-empty, further restricts accepted
+// signers by principal.
+func NewSSHAllowedSignersVerifier(allowedSignersSecret string, secrets map[string]string, allowedSigners []config.DirectoryContentsGitAllowedSigner) Verifier {
+	return &sshAllowedSignersVerifier{allowedSignersSecret: allowedSignersSecret, secrets: secrets, allowedSigners: allowedSigners}
+}
+
+func (v *sshAllowedSignersVerifier) Verify(_ context.Context, _ string, payload io.Reader, meta map[string]string) error {
+	sig, found := meta[MetaSignature]
+	if !found {
+		return fmt.Errorf("Expected %s to be set in verifier metadata", MetaSignature)
+	}
+
+	parsedSig, err := parseArmoredSSHSignature(sig)
+	if err != nil {
+		return fmt.Errorf("Parsing SSH signature: %s", err)
+	}
+	if parsedSig.namespace != sshSigNamespace {
+		return fmt.Errorf("Expected SSH signature namespace '%s', got '%s'", sshSigNamespace, parsedSig.namespace)
+	}
+
+	data, found := v.secrets[v.allowedSignersSecret]
+	if !found {
+		return fmt.Errorf("Expected to find secret '%s' for verification", v.allowedSignersSecret)
+	}
+
+	allowedSigners, err := parseAllowedSigners(data)
+	if err != nil {
+		return fmt.Errorf("Parsing allowed_signers from secret '%s': %s", v.allowedSignersSecret, err)
+	}
+
+	matched := findAllowedSigner(allowedSigners, parsedSig.publicKey)
+	if matched == nil {
+		return fmt.Errorf("ssh: signature made by unknown entity (public key not found in allowed_signers)")
+	}
+
+	payloadBytes, err := io.ReadAll(payload)
+	if err != nil {
+		return fmt.Errorf("Reading signed payload: %s", err)
+	}
+
+	digest, err := hashPayload(parsedSig.hashAlgorithm, payloadBytes)
+	if err != nil {
+		return err
+	}
+
+	signedData := sshSignedData(parsedSig.namespace, parsedSig.hashAlgorithm, digest)
+	if err := parsedSig.publicKey.Verify(signedData, &ssh.Signature{
+		Format: parsedSig.signatureFmt,
+		Blob:   parsedSig.signature,
+	}); err != nil {
+		return err
+	}
+
+	return checkAllowedPrincipal(matched.principals, v.allowedSigners)
+}
+
+func checkAllowedPrincipal(principals []string, allowedSigners []config.DirectoryContentsGitAllowedSigner) error {
+	if len(allowedSigners) == 0 {
+		return nil
+	}
+
+	for _, allowed := range allowedSigners {
+		if allowed.Principal == "" {
+			continue
+		}
+		for _, principal := range principals {
+			if principal == allowed.Principal {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("signature made by %s not in allowedSigners", strings.Join(principals, ","))
+}
+
+type sshSignature struct {
+	publicKey     ssh.PublicKey
+	namespace     string
+	hashAlgorithm string
+	signature     []byte
+	signatureFmt  string
+}
+
+func sshSignedData(namespace, hashAlgorithm string, digest []byte) []byte {
+	return ssh.Marshal(struct {
+		Magic     [6]byte
+		Namespace string
+		Reserved  string
+		HashAlgo  string
+		Digest    string
+	}{
+		Magic:     [6]byte{'S', 'S', 'H', 'S', 'I', 'G'},
+		Namespace: namespace,
+		Reserved:  "",
+		HashAlgo:  hashAlgorithm,
+		Digest:    string(digest),
+	})
+}
+
+func hashPayload(algo string, payload []byte) ([]byte, error) {
+	var h hash.Hash
+	switch algo {
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return nil, fmt.Errorf("Unsupported SSH signature hash algorithm '%s'", algo)
+	}
+	h.Write(payload)
+	return h.Sum(nil), nil
+}
+
+func parseArmoredSSHSignature(armored string) (*sshSignature, error) {
+	block, _ := pem.Decode([]byte(strings.TrimSpace(armored)))
+	if block == nil {
+		return nil, fmt.Errorf("Decoding armored SSH signature")
+	}
+
+	var wire struct {
+		Magic         [6]byte
+		Version       uint32
+		PublicKey     string
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Signature     string
+	}
+	if err := ssh.Unmarshal(block.Bytes, &wire); err != nil {
+		return nil, fmt.Errorf("Unmarshaling SSHSIG structure: %s", err)
+	}
+	if string(wire.Magic[:]) != sshSigMagic {
+		return nil, fmt.Errorf("Expected SSHSIG magic preamble, got '%s'", wire.Magic)
+	}
+
+	pubKey, err := ssh.ParsePublicKey([]byte(wire.PublicKey))
+	if err != nil {
+		return nil, fmt.Errorf("Parsing public key from signature: %s", err)
+	}
+
+	var sig ssh.Signature
+	if err := ssh.Unmarshal([]byte(wire.Signature), &sig); err != nil {
+		return nil, fmt.Errorf("Unmarshaling signature blob: %s", err)
+	}
+
+	return &sshSignature{
+		publicKey:     pubKey,
+		namespace:     wire.Namespace,
+		hashAlgorithm: wire.HashAlgorithm,
+		signature:     sig.Blob,
+		signatureFmt:  sig.Format,
+	}, nil
+}
+
+type allowedSigner struct {
+	principals []string
+	key        ssh.PublicKey
+}
+
+func parseAllowedSigners(data string) ([]allowedSigner, error) {
+	var signers []allowedSigner
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		for i, field := range fields {
+			if !looksLikeSSHKeyType(field) {
+				continue
+			}
+			if i+1 >= len(fields) {
+				continue
+			}
+			keyBytes, err := base64.StdEncoding.DecodeString(fields[i+1])
+			if err != nil {
+				continue
+			}
+			key, err := ssh.ParsePublicKey(keyBytes)
+			if err != nil {
+				continue
+			}
+			signers = append(signers, allowedSigner{
+				principals: strings.Split(fields[0], ","),
+				key:        key,
+			})
+			break
+		}
+	}
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("Expected to find at least one key in allowed_signers")
+	}
+	return signers, nil
+}
+
+func findAllowedSigner(signers []allowedSigner, pubKey ssh.PublicKey) *allowedSigner {
+	for i := range signers {
+		if signers[i].key.Type() == pubKey.Type() && string(signers[i].key.Marshal()) == string(pubKey.Marshal()) {
+			return &signers[i]
+		}
+	}
+	return nil
+}
+
+func looksLikeSSHKeyType(s string) bool {
+	switch {
+	case s == "ssh-rsa", s == "ssh-ed25519", s == "ssh-dss":
+		return true
+	case strings.HasPrefix(s, "ecdsa-sha2-"):
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package verify
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/vendir/pkg/vendir/config"
+)
+
+// TestSSHAllowedSignersVerifier exercises sshAllowedSignersVerifier
+// purely through the Verifier interface returned by its exported
+// constructor (NewSSHAllowedSignersVerifier), rather than through the
+// git fetcher's commit/tag signature extraction.
+func TestSSHAllowedSignersVerifier(t *testing.T) {
+	trusted, trustedSigner := newSSHKeypair(t)
+	_, strangerSigner := newSSHKeypair(t)
+
+	payload := "artifact contents\n"
+	sig := signSSH(t, trustedSigner, payload)
+
+	secrets := map[string]string{
+		"allowed-signers": fmt.Sprintf("trusted@example.com %s\n", allowedSignerLine(trusted)),
+	}
+
+	t.Run("signed by a key in allowed_signers", func(t *testing.T) {
+		v := NewSSHAllowedSignersVerifier("allowed-signers", secrets, nil)
+		err := v.Verify(context.Background(), "artifact", strings.NewReader(payload), map[string]string{MetaSignature: sig})
+		require.NoError(t, err)
+	})
+
+	t.Run("signed by a key not in allowed_signers", func(t *testing.T) {
+		strangerSig := signSSH(t, strangerSigner, payload)
+		v := NewSSHAllowedSignersVerifier("allowed-signers", secrets, nil)
+		err := v.Verify(context.Background(), "artifact", strings.NewReader(payload), map[string]string{MetaSignature: strangerSig})
+		require.Error(t, err)
+	})
+
+	t.Run("trusted key but principal not in allowedSigners", func(t *testing.T) {
+		v := NewSSHAllowedSignersVerifier("allowed-signers", secrets, []config.DirectoryContentsGitAllowedSigner{{Principal: "someone-else@example.com"}})
+		err := v.Verify(context.Background(), "artifact", strings.NewReader(payload), map[string]string{MetaSignature: sig})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not in allowedSigners")
+	})
+
+	t.Run("trusted key with matching principal", func(t *testing.T) {
+		v := NewSSHAllowedSignersVerifier("allowed-signers", secrets, []config.DirectoryContentsGitAllowedSigner{{Principal: "trusted@example.com"}})
+		err := v.Verify(context.Background(), "artifact", strings.NewReader(payload), map[string]string{MetaSignature: sig})
+		require.NoError(t, err)
+	})
+}
+
+func newSSHKeypair(t *testing.T) (ssh.PublicKey, ssh.Signer) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(priv)
+	require.NoError(t, err)
+	sshPub, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+	return sshPub, signer
+}
+
+func allowedSignerLine(pub ssh.PublicKey) string {
+	return strings.TrimSuffix(string(ssh.MarshalAuthorizedKey(pub)), "\n")
+}
+
+func signSSH(t *testing.T, signer ssh.Signer, payload string) string {
+	t.Helper()
+
+	digest, err := hashPayload("sha512", []byte(payload))
+	require.NoError(t, err)
+
+	signedData := sshSignedData(sshSigNamespace, "sha512", digest)
+	sig, err := signer.Sign(rand.Reader, signedData)
+	require.NoError(t, err)
+
+	wire := struct {
+		Magic         [6]byte
+		Version       uint32
+		PublicKey     string
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Signature     string
+	}{
+		Magic:         [6]byte{'S', 'S', 'H', 'S', 'I', 'G'},
+		Version:       1,
+		PublicKey:     string(signer.PublicKey().Marshal()),
+		Namespace:     sshSigNamespace,
+		HashAlgorithm: "sha512",
+		Signature:     string(ssh.Marshal(sig)),
+	}
+
+	block := &pem.Block{Type: "SSH SIGNATURE", Bytes: ssh.Marshal(wire)}
+	return string(pem.EncodeToMemory(block))
+}
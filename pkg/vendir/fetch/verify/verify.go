@@ -0,0 +1,35 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package verify defines a content-type-agnostic signature
+// verification interface, and the concrete verifiers that git,
+// http, image and imgpkgBundle sources select between based on
+// their verification config.
+package verify
+
+import (
+	"context"
+	"io"
+)
+
+// Verifier checks that the content at artifactRef carries a
+// signature it trusts. payload is read by verifiers that need the
+// content itself; callers may pass nil when meta already carries
+// what the verifier checks (e.g. MetaDigest). meta carries any
+// ancillary, verifier-specific data the caller already has on hand
+// (e.g. a detached signature blob, or a digest) so that Verifier
+// implementations don't need to know how to fetch it themselves.
+type Verifier interface {
+	Verify(ctx context.Context, artifactRef string, payload io.Reader, meta map[string]string) error
+}
+
+// Well-known meta keys populated by callers before invoking Verify.
+const (
+	// MetaSignature carries a detached signature (armored PGP,
+	// binary PGP, or armored SSH) alongside the payload it signs.
+	MetaSignature = "signature"
+	// MetaDigest carries the artifactRef's resolved content digest
+	// (e.g. "sha256:...") for verifiers that check it against a
+	// signed subject rather than hashing payload themselves.
+	MetaDigest = "digest"
+)
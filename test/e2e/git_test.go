@@ -73,6 +73,34 @@ directories:
 		return yamlConfigWithPubKeys(ref, trustedPubKey)
 	}
 
+	yamlConfigWithAllowedSigners := func(ref string, allowedSignersYAML string) io.Reader {
+		encodedPubKeys := base64.StdEncoding.EncodeToString([]byte(trustedPubKey))
+		repoPath := filepath.Join(gitSrcPath, "git-repo")
+		return strings.NewReader(fmt.Sprintf(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: git-pubs
+data:
+  valid.pub: "%s"
+---
+apiVersion: vendir.k14s.io/v1alpha1
+kind: Config
+directories:
+- path: vendor
+  contents:
+  - path: test
+    git:
+      url: "%s"
+      ref: "%s"
+      verification:
+        publicKeysSecretRef:
+          name: git-pubs
+        allowedSigners:
+%s
+`, encodedPubKeys, repoPath, ref, allowedSignersYAML))
+	}
+
 	logger.Section("signed trusted commit", func() {
 		ref := strings.TrimSpace(readFile(t, filepath.Join(gitSrcPath, "git-meta/signed-trusted-commit.txt")))
 		vendir.RunWithOpts([]string{"sync", "-f", "-"}, RunOpts{Dir: dstPath, StdinReader: yamlConfig(ref)})
@@ -86,6 +114,21 @@ directories:
 		vendir.RunWithOpts([]string{"sync", "-f", "-"}, RunOpts{Dir: dstPath, StdinReader: config})
 	})
 
+	logger.Section("trusted key but disallowed identity", func() {
+		ref := strings.TrimSpace(readFile(t, filepath.Join(gitSrcPath, "git-meta/signed-trusted-commit.txt")))
+		config := yamlConfigWithAllowedSigners(ref, "        - email: someone-else@example.com")
+		_, err := vendir.RunWithOpts([]string{"sync", "-f", "-"}, RunOpts{Dir: dstPath, StdinReader: config, AllowError: true})
+		assert.Error(t, err, "Expected to err when signer identity is not in allowedSigners")
+		assert.ErrorContains(t, err, "not in allowedSigners", "Expected err to indicate disallowed identity")
+	})
+
+	logger.Section("trusted key with matching identity", func() {
+		ref := strings.TrimSpace(readFile(t, filepath.Join(gitSrcPath, "git-meta/signed-trusted-commit.txt")))
+		trustedEmail := strings.TrimSpace(readFile(t, filepath.Join(gitSrcPath, "git-meta/trusted-email.txt")))
+		config := yamlConfigWithAllowedSigners(ref, fmt.Sprintf("        - email: %s", trustedEmail))
+		vendir.RunWithOpts([]string{"sync", "-f", "-"}, RunOpts{Dir: dstPath, StdinReader: config})
+	})
+
 	logger.Section("signed trusted tag", func() {
 		ref := "signed-trusted-tag"
 		vendir.RunWithOpts([]string{"sync", "-f", "-"}, RunOpts{Dir: dstPath, StdinReader: yamlConfig(ref)})
@@ -136,6 +179,92 @@ directories:
 	})
 }
 
+func TestGitVerificationSSH(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	vendir := Vendir{t, env.BinaryPath, logger}
+
+	gitSrcPath, err := os.MkdirTemp("", "vendir-e2e-git-verify-signed-ssh-git-repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(gitSrcPath)
+
+	out, err := exec.Command("tar", "xzvf", "assets/git-repo-signed-ssh/asset.tgz", "-C", gitSrcPath).CombinedOutput()
+	if err != nil {
+		t.Fatalf("Unpacking git-repo-signed-ssh asset: %s (output: '%s')", err, out)
+	}
+
+	dstPath, err := os.MkdirTemp("", "vendir-e2e-git-verify-signed-ssh-dst")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dstPath)
+
+	trustedAllowedSigners := readFile(t, filepath.Join(gitSrcPath, "keys/trusted.allowed_signers"))
+
+	yamlConfigWithAllowedSigners := func(ref string, allowedSigners string) io.Reader {
+		encodedAllowedSigners := base64.StdEncoding.EncodeToString([]byte(allowedSigners))
+		repoPath := filepath.Join(gitSrcPath, "git-repo")
+		return strings.NewReader(fmt.Sprintf(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: git-ssh-signers
+data:
+  allowed_signers: "%s"
+---
+apiVersion: vendir.k14s.io/v1alpha1
+kind: Config
+directories:
+- path: vendor
+  contents:
+  - path: test
+    git:
+      url: "%s"
+      ref: "%s"
+      verification:
+        sshAllowedSignersSecretRef:
+          name: git-ssh-signers
+`, encodedAllowedSigners, repoPath, ref))
+	}
+
+	yamlConfig := func(ref string) io.Reader {
+		return yamlConfigWithAllowedSigners(ref, trustedAllowedSigners)
+	}
+
+	logger.Section("ssh signed trusted commit", func() {
+		ref := strings.TrimSpace(readFile(t, filepath.Join(gitSrcPath, "git-meta/ssh-signed-trusted-commit.txt")))
+		vendir.RunWithOpts([]string{"sync", "-f", "-"}, RunOpts{Dir: dstPath, StdinReader: yamlConfig(ref)})
+	})
+
+	logger.Section("ssh signed trusted tag", func() {
+		ref := "ssh-signed-trusted-tag"
+		vendir.RunWithOpts([]string{"sync", "-f", "-"}, RunOpts{Dir: dstPath, StdinReader: yamlConfig(ref)})
+	})
+
+	logger.Section("ssh signed stranger commit", func() {
+		ref := strings.TrimSpace(readFile(t, filepath.Join(gitSrcPath, "git-meta/ssh-signed-stranger-commit.txt")))
+		_, err := vendir.RunWithOpts([]string{"sync", "-f", "-"}, RunOpts{Dir: dstPath, StdinReader: yamlConfig(ref), AllowError: true})
+		assert.Error(t, err, "Expected to err when commit is signed by stranger")
+		assert.ErrorContains(t, err, "ssh: signature made by unknown entity", "Expected err to indicate stranger signing failure")
+	})
+
+	logger.Section("ssh signed stranger tag", func() {
+		ref := "ssh-signed-stranger-tag"
+		_, err := vendir.RunWithOpts([]string{"sync", "-f", "-"}, RunOpts{Dir: dstPath, StdinReader: yamlConfig(ref), AllowError: true})
+		assert.Error(t, err, "Expected to err when commit is signed by stranger")
+		assert.ErrorContains(t, err, "ssh: signature made by unknown entity", "Expected err to indicate stranger signing failure")
+	})
+
+	logger.Section("unsigned commit fails ssh verification", func() {
+		ref := strings.TrimSpace(readFile(t, filepath.Join(gitSrcPath, "git-meta/unsigned-commit.txt")))
+		_, err := vendir.RunWithOpts([]string{"sync", "-f", "-"}, RunOpts{Dir: dstPath, StdinReader: yamlConfig(ref), AllowError: true})
+		assert.Error(t, err, "Expected to err when commit is unsigned")
+		assert.ErrorContains(t, err, "Expected to find commit signature:", "Expected err to indicate missing signature")
+	})
+}
+
 func TestGitCache(t *testing.T) {
 	env := BuildEnv(t)
 	logger := Logger{}
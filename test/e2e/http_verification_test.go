@@ -0,0 +1,86 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPVerification(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	vendir := Vendir{t, env.BinaryPath, logger}
+
+	assetsPath, err := filepath.Abs("assets/http-signed-artifact")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	artifact := readFile(t, filepath.Join(assetsPath, "artifact.txt"))
+	sig := readFile(t, filepath.Join(assetsPath, "artifact.txt.asc"))
+	trustedPubKey := readFile(t, filepath.Join(assetsPath, "keys/trusted.pub"))
+	strangerPubKey := readFile(t, filepath.Join(assetsPath, "keys/stranger.pub"))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/artifact.txt":
+			fmt.Fprint(w, artifact)
+		case "/artifact.txt.asc":
+			fmt.Fprint(w, sig)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	dstPath, err := os.MkdirTemp("", "vendir-e2e-http-verify-dst")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dstPath)
+
+	yamlConfig := func(pubKey string) string {
+		encodedPubKey := base64.StdEncoding.EncodeToString([]byte(pubKey))
+		return fmt.Sprintf(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: http-pubs
+data:
+  valid.pub: "%s"
+---
+apiVersion: vendir.k14s.io/v1alpha1
+kind: Config
+directories:
+- path: vendor
+  contents:
+  - path: test
+    http:
+      url: "%s/artifact.txt"
+      verification:
+        pgp:
+          publicKeysSecretRef:
+            name: http-pubs
+`, encodedPubKey, srv.URL)
+	}
+
+	logger.Section("artifact signed with trusted key", func() {
+		vendir.RunWithOpts([]string{"sync", "-f", "-"}, RunOpts{Dir: dstPath, StdinReader: strings.NewReader(yamlConfig(trustedPubKey))})
+	})
+
+	logger.Section("artifact verified against stranger key", func() {
+		_, err := vendir.RunWithOpts([]string{"sync", "-f", "-"}, RunOpts{Dir: dstPath, StdinReader: strings.NewReader(yamlConfig(strangerPubKey)), AllowError: true})
+		assert.Error(t, err, "Expected to err when artifact is verified against an unrelated key")
+		assert.ErrorContains(t, err, "openpgp: signature made by unknown entity", "Expected err to indicate stranger signing failure")
+	})
+}
@@ -0,0 +1,142 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageVerification(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	vendir := Vendir{t, env.BinaryPath, logger}
+
+	assetsPath, err := filepath.Abs("assets/cosign-signed-image")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	imageRef := strings.TrimSpace(readFile(t, filepath.Join(assetsPath, "image-ref.txt")))
+	trustedPubKey := readFile(t, filepath.Join(assetsPath, "keys/trusted.pub"))
+	strangerPubKey := readFile(t, filepath.Join(assetsPath, "keys/stranger.pub"))
+
+	dstPath, err := os.MkdirTemp("", "vendir-e2e-image-verify-dst")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dstPath)
+
+	yamlConfig := func(pubKey string) string {
+		encodedPubKey := base64.StdEncoding.EncodeToString([]byte(pubKey))
+		return fmt.Sprintf(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: cosign-pubs
+data:
+  cosign.pub: "%s"
+---
+apiVersion: vendir.k14s.io/v1alpha1
+kind: Config
+directories:
+- path: vendor
+  contents:
+  - path: test
+    image:
+      url: "%s"
+      verification:
+        cosign:
+          publicKeysSecretRef:
+            name: cosign-pubs
+`, encodedPubKey, imageRef)
+	}
+
+	logger.Section("image signed with trusted key", func() {
+		vendir.RunWithOpts([]string{"sync", "-f", "-"}, RunOpts{Dir: dstPath, StdinReader: strings.NewReader(yamlConfig(trustedPubKey))})
+	})
+
+	logger.Section("image signed but verified against stranger key", func() {
+		_, err := vendir.RunWithOpts([]string{"sync", "-f", "-"}, RunOpts{Dir: dstPath, StdinReader: strings.NewReader(yamlConfig(strangerPubKey)), AllowError: true})
+		assert.Error(t, err, "Expected to err when image is verified against an unrelated key")
+		assert.ErrorContains(t, err, "Verifying image", "Expected err to indicate cosign verification failure")
+	})
+}
+
+func TestImageVerificationKeyless(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	vendir := Vendir{t, env.BinaryPath, logger}
+
+	assetsPath, err := filepath.Abs("assets/cosign-keyless-signed-image")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	imageRef := strings.TrimSpace(readFile(t, filepath.Join(assetsPath, "image-ref.txt")))
+	fulcioRoots := readFile(t, filepath.Join(assetsPath, "keys/fulcio-roots.pem"))
+	rekorPubKey := readFile(t, filepath.Join(assetsPath, "keys/rekor.pub"))
+	identity := strings.TrimSpace(readFile(t, filepath.Join(assetsPath, "signer-identity.txt")))
+	issuer := strings.TrimSpace(readFile(t, filepath.Join(assetsPath, "signer-issuer.txt")))
+
+	dstPath, err := os.MkdirTemp("", "vendir-e2e-image-verify-keyless-dst")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dstPath)
+
+	yamlConfig := func(identity, issuer string, requireTLog bool) string {
+		encodedRoots := base64.StdEncoding.EncodeToString([]byte(fulcioRoots))
+		encodedRekor := base64.StdEncoding.EncodeToString([]byte(rekorPubKey))
+		return fmt.Sprintf(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: cosign-fulcio-roots
+data:
+  fulcio-roots.pem: "%s"
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: cosign-rekor
+data:
+  rekor.pub: "%s"
+---
+apiVersion: vendir.k14s.io/v1alpha1
+kind: Config
+directories:
+- path: vendor
+  contents:
+  - path: test
+    image:
+      url: "%s"
+      verification:
+        cosign:
+          certificateIdentity: "%s"
+          certificateOIDCIssuer: "%s"
+          fulcioRootsSecretRef:
+            name: cosign-fulcio-roots
+          rekorPublicKeysSecretRef:
+            name: cosign-rekor
+          requireTLog: %t
+`, encodedRoots, encodedRekor, imageRef, identity, issuer, requireTLog)
+	}
+
+	logger.Section("image signed keylessly and verified against matching identity, with tlog", func() {
+		vendir.RunWithOpts([]string{"sync", "-f", "-"}, RunOpts{Dir: dstPath, StdinReader: strings.NewReader(yamlConfig(identity, issuer, true))})
+	})
+
+	logger.Section("image signed keylessly but verified against a different identity", func() {
+		_, err := vendir.RunWithOpts([]string{"sync", "-f", "-"}, RunOpts{Dir: dstPath, StdinReader: strings.NewReader(yamlConfig("someone-else@example.com", issuer, true)), AllowError: true})
+		assert.Error(t, err, "Expected to err when certificate identity does not match")
+		assert.ErrorContains(t, err, "Verifying image", "Expected err to indicate cosign verification failure")
+	})
+}